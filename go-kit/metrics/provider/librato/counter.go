@@ -0,0 +1,53 @@
+package librato
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Counter is a monotonic counter: each batch interval it reports the delta
+// observed since the previous interval as a Librato gauge, then resets.
+// This lets consumers compute accurate averages between scrapes regardless
+// of scrape frequency, which a plain gauge can't do.
+type Counter struct {
+	p     *Provider
+	name  string
+	lvs   []string
+	attrs map[string]interface{}
+	bits  uint64
+}
+
+func newCounter(p *Provider, name string, attrs map[string]interface{}, lvs ...string) *Counter {
+	return &Counter{p: p, name: name, attrs: attrs, lvs: lvs}
+}
+
+// With returns a new Counter with the label values appended to this one's,
+// registered with the same Provider so it's picked up the next time the
+// Provider samples.
+func (c *Counter) With(labelValues ...string) *Counter {
+	nc := &Counter{p: c.p, name: c.name, attrs: c.attrs, lvs: append(append([]string{}, c.lvs...), labelValues...)}
+	c.p.registerCounter(nc)
+	return nc
+}
+
+// Add adds delta to the counter.
+func (c *Counter) Add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&c.bits)
+		new := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&c.bits, old, new) {
+			return
+		}
+	}
+}
+
+// delta returns the value accumulated since the last reset, and resets it
+// to zero.
+func (c *Counter) delta() float64 {
+	old := atomic.SwapUint64(&c.bits, 0)
+	return math.Float64frombits(old)
+}
+
+func (c *Counter) metricName() string {
+	return metricName(c.name, c.lvs)
+}
@@ -0,0 +1,43 @@
+package librato
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCounterDeltaResets(t *testing.T) {
+	c := newCounter(nil, "test.counter", nil)
+	c.Add(3)
+	c.Add(4)
+
+	if got := c.delta(); got != 7 {
+		t.Fatalf("delta() = %v, want 7", got)
+	}
+	if got := c.delta(); got != 0 {
+		t.Fatalf("delta() after a reset = %v, want 0", got)
+	}
+}
+
+func TestCounterConcurrentAdd(t *testing.T) {
+	c := newCounter(nil, "test.counter", nil)
+
+	const goroutines = 8
+	const perGoroutine = 1000
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				c.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	want := float64(goroutines * perGoroutine)
+	if got := c.delta(); got != want {
+		t.Errorf("delta() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package librato
+
+import "sync"
+
+// Gauge is a Librato gauge: it reports whatever value was last Set.
+type Gauge struct {
+	mu    sync.Mutex
+	p     *Provider
+	name  string
+	lvs   []string
+	attrs map[string]interface{}
+	value float64
+}
+
+func newGauge(p *Provider, name string, attrs map[string]interface{}, lvs ...string) *Gauge {
+	return &Gauge{p: p, name: name, attrs: attrs, lvs: lvs}
+}
+
+// With returns a new Gauge with the label values appended to this one's,
+// registered with the same Provider so it's picked up the next time the
+// Provider samples.
+func (g *Gauge) With(labelValues ...string) *Gauge {
+	ng := &Gauge{p: g.p, name: g.name, attrs: g.attrs, lvs: append(append([]string{}, g.lvs...), labelValues...)}
+	g.p.registerGauge(ng)
+	return ng
+}
+
+// Set sets the gauge's current value.
+func (g *Gauge) Set(value float64) {
+	g.mu.Lock()
+	g.value = value
+	g.mu.Unlock()
+}
+
+// Add adds delta to the gauge's current value.
+func (g *Gauge) Add(delta float64) {
+	g.mu.Lock()
+	g.value += delta
+	g.mu.Unlock()
+}
+
+func (g *Gauge) snapshot() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.value
+}
+
+func (g *Gauge) metricName() string {
+	return metricName(g.name, g.lvs)
+}
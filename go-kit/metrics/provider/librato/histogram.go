@@ -0,0 +1,180 @@
+package librato
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Histogram accumulates observations between batch intervals and reports as
+// a Report carrying the aggregate (count/sum/min/max/sum-of-squares/stddev)
+// plus p50/p95/p99.
+type Histogram struct {
+	mu   sync.Mutex
+	p    *Provider
+	name string
+	lvs  []string
+
+	// attrs, when non-nil, is attached to every Report this histogram
+	// produces. Timer uses it to carry Librato display attributes.
+	attrs map[string]interface{}
+
+	count int64
+	sum   float64
+	min   float64
+	max   float64
+	sumsq float64
+	h     *quantileSample
+}
+
+func newHistogram(p *Provider, name string, lvs ...string) *Histogram {
+	return &Histogram{
+		p:    p,
+		name: name,
+		lvs:  lvs,
+		h:    &quantileSample{},
+	}
+}
+
+// With returns a new Histogram with the label values appended to this
+// one's, registered with the same Provider so it's picked up the next time
+// the Provider samples.
+func (h *Histogram) With(labelValues ...string) *Histogram {
+	nh := &Histogram{
+		p:     h.p,
+		name:  h.name,
+		lvs:   append(append([]string{}, h.lvs...), labelValues...),
+		attrs: h.attrs,
+		h:     &quantileSample{},
+	}
+	h.p.registerHistogram(nh)
+	return nh
+}
+
+// Observe records a value in the histogram.
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == 0 || value < h.min {
+		h.min = value
+	}
+	if h.count == 0 || value > h.max {
+		h.max = value
+	}
+	h.count++
+	h.sum += value
+	h.sumsq += value * value
+	h.h.Insert(value)
+}
+
+// reset clears the histogram's accumulated state. Callers must hold h.mu.
+func (h *Histogram) reset() {
+	h.count = 0
+	h.sum = 0
+	h.min = 0
+	h.max = 0
+	h.sumsq = 0
+	h.h = &quantileSample{}
+}
+
+func (h *Histogram) metricName() string {
+	return metricName(h.name, h.lvs)
+}
+
+// Snapshot atomically captures the histogram's accumulated state and swaps
+// in a fresh, empty bucket for subsequent observations. The returned
+// HistogramSnapshot is immutable and safe to read (including computing
+// quantiles or JSON-encoding it) without holding the histogram's lock.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := HistogramSnapshot{
+		count: h.count,
+		sum:   h.sum,
+		min:   h.min,
+		max:   h.max,
+		sumsq: h.sumsq,
+		h:     h.h,
+	}
+	h.reset()
+	return s
+}
+
+// HistogramSnapshot is a read-only, point-in-time view of a Histogram's
+// state as of the last call to Snapshot.
+type HistogramSnapshot struct {
+	count int64
+	sum   float64
+	min   float64
+	max   float64
+	sumsq float64
+	h     *quantileSample
+}
+
+// Count returns the number of observations in the snapshot.
+func (s HistogramSnapshot) Count() int64 { return s.count }
+
+// Sum returns the sum of observations in the snapshot.
+func (s HistogramSnapshot) Sum() float64 { return s.sum }
+
+// Min returns the smallest observation in the snapshot.
+func (s HistogramSnapshot) Min() float64 { return s.min }
+
+// Max returns the largest observation in the snapshot.
+func (s HistogramSnapshot) Max() float64 { return s.max }
+
+// SumSq returns the sum of the squares of observations in the snapshot.
+func (s HistogramSnapshot) SumSq() float64 { return s.sumsq }
+
+// Quantile returns the value at quantile q (0..1) among the snapshot's
+// observations.
+func (s HistogramSnapshot) Quantile(q float64) float64 { return s.h.Quantile(q) }
+
+// report snapshots the histogram into a Report, or returns ok=false if
+// nothing was observed this interval.
+func (h *Histogram) report(period int, now time.Time) (r Report, ok bool) {
+	s := h.Snapshot()
+	if s.Count() == 0 {
+		return Report{}, false
+	}
+	return Report{
+		Name:       h.metricName(),
+		Tags:       labelValuesToTags(h.lvs...),
+		Attributes: h.attrs,
+		Count:      s.Count(),
+		Sum:        s.Sum(),
+		Min:        s.Min(),
+		Max:        s.Max(),
+		SumSq:      s.SumSq(),
+		Last:       s.Sum() / float64(s.Count()),
+		StdDev:     stddev(s.Sum(), s.SumSq(), s.Count()),
+		Percentiles: map[string]float64{
+			"50": s.Quantile(.50),
+			"95": s.Quantile(.95),
+			"99": s.Quantile(.99),
+		},
+		Period:    period,
+		Timestamp: now,
+	}, true
+}
+
+// quantileSample is a minimal quantile estimator: it keeps every observation
+// from the current batch interval and sorts on read. That's fine given how
+// few samples land within a single interval between Librato reports.
+type quantileSample struct {
+	values []float64
+}
+
+func (s *quantileSample) Insert(v float64) {
+	s.values = append(s.values, v)
+}
+
+func (s *quantileSample) Quantile(q float64) float64 {
+	if len(s.values) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, s.values...)
+	sort.Float64s(sorted)
+	idx := int(q * float64(len(sorted)-1))
+	return sorted[idx]
+}
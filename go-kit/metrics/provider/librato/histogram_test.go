@@ -0,0 +1,84 @@
+package librato
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestQuantileSample(t *testing.T) {
+	s := &quantileSample{}
+	for _, v := range []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10} {
+		s.Insert(v)
+	}
+
+	if got := s.Quantile(0); got != 1 {
+		t.Errorf("Quantile(0) = %v, want 1", got)
+	}
+	if got := s.Quantile(1); got != 10 {
+		t.Errorf("Quantile(1) = %v, want 10", got)
+	}
+	if got := s.Quantile(.5); got != 5 {
+		t.Errorf("Quantile(.5) = %v, want 5", got)
+	}
+}
+
+func TestQuantileSampleEmpty(t *testing.T) {
+	s := &quantileSample{}
+	if got := s.Quantile(.99); got != 0 {
+		t.Errorf("Quantile(.99) on an empty sample = %v, want 0", got)
+	}
+}
+
+func TestHistogramSnapshotAndReset(t *testing.T) {
+	h := newHistogram(nil, "test.histogram")
+	h.Observe(1)
+	h.Observe(2)
+	h.Observe(3)
+
+	s := h.Snapshot()
+	if s.Count() != 3 {
+		t.Fatalf("Count() = %d, want 3", s.Count())
+	}
+	if s.Sum() != 6 {
+		t.Fatalf("Sum() = %v, want 6", s.Sum())
+	}
+	if s.Min() != 1 || s.Max() != 3 {
+		t.Fatalf("Min()/Max() = %v/%v, want 1/3", s.Min(), s.Max())
+	}
+
+	// Snapshot must atomically clear the histogram, so a second snapshot
+	// taken before any further observations is empty.
+	again := h.Snapshot()
+	if again.Count() != 0 {
+		t.Fatalf("Count() after Snapshot = %d, want 0", again.Count())
+	}
+}
+
+func TestHistogramSnapshotConcurrentObserve(t *testing.T) {
+	h := newHistogram(nil, "test.histogram")
+
+	const goroutines = 8
+	const perGoroutine = 1000
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				h.Observe(1)
+			}
+		}()
+	}
+
+	var total int64
+	for total < goroutines*perGoroutine {
+		total += h.Snapshot().Count()
+	}
+	wg.Wait()
+	total += h.Snapshot().Count()
+
+	if total != goroutines*perGoroutine {
+		t.Errorf("total observations seen across snapshots = %d, want %d", total, goroutines*perGoroutine)
+	}
+}
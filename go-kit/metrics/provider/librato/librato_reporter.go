@@ -0,0 +1,275 @@
+package librato
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	legacyPath = "/v1/metrics"
+	taggedPath = "/v1/measurements"
+)
+
+// LibratoLegacy ships Reports to Librato's legacy v1/metrics API, which
+// predates tag support and instead folds percentiles into separate dotted
+// gauge names built from PercentilePrefix.
+type LibratoLegacy struct {
+	URL    *url.URL
+	Source string
+
+	BatchSize        int
+	SSA              bool
+	PercentilePrefix string
+
+	Client *http.Client
+}
+
+// NewLibratoLegacy returns a LibratoLegacy posting to u on behalf of source.
+func NewLibratoLegacy(u *url.URL, source string) *LibratoLegacy {
+	return &LibratoLegacy{
+		URL:              u,
+		Source:           source,
+		BatchSize:        defaultBatchSize,
+		PercentilePrefix: ".p",
+		Client:           http.DefaultClient,
+	}
+}
+
+// Report translates reports into Librato's legacy wire format and posts them.
+func (r *LibratoLegacy) Report(reports []Report) error {
+	requests, err := r.legacyRequests(reports)
+	if err != nil {
+		return err
+	}
+	return doRequests(r.Client, requests)
+}
+
+func (r *LibratoLegacy) legacyRequests(reports []Report) ([]*http.Request, error) {
+	gauges := make([]gauge, 0, len(reports))
+	for _, rep := range reports {
+		gauges = append(gauges, legacyGauges(rep, r.PercentilePrefix)...)
+	}
+	if len(gauges) == 0 {
+		return nil, nil
+	}
+
+	dest, user := resolveDestination(r.URL, legacyPath)
+	measureTime := reports[0].Timestamp.Truncate(time.Duration(reports[0].Period) * time.Second).Unix()
+	batchSize := batchSizeOrDefault(r.BatchSize)
+
+	nextEnd := func(e int) int {
+		e += batchSize
+		if l := len(gauges); e > l {
+			return l
+		}
+		return e
+	}
+
+	requests := make([]*http.Request, 0, len(gauges)/batchSize+1)
+	for batch, e := 0, nextEnd(0); batch < len(gauges); batch, e = e, nextEnd(e) {
+		body := struct {
+			Source      string                 `json:"source,omitempty"`
+			MeasureTime int64                  `json:"measure_time"`
+			Gauges      []gauge                `json:"gauges"`
+			Attributes  map[string]interface{} `json:"attributes,omitempty"`
+		}{
+			Source:      r.Source,
+			MeasureTime: measureTime,
+			Gauges:      gauges[batch:e],
+		}
+		if r.SSA {
+			body.Attributes = map[string]interface{}{"aggregate": true}
+		}
+
+		req, err := newJSONRequest(dest, user, body)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, nil
+}
+
+// LibratoTagged ships Reports to Librato's tagged v1/measurements API.
+type LibratoTagged struct {
+	URL    *url.URL
+	Source string
+
+	BatchSize        int
+	PercentilePrefix string
+
+	Client *http.Client
+}
+
+// NewLibratoTagged returns a LibratoTagged posting to u on behalf of source.
+func NewLibratoTagged(u *url.URL, source string) *LibratoTagged {
+	return &LibratoTagged{
+		URL:              u,
+		Source:           source,
+		BatchSize:        defaultBatchSize,
+		PercentilePrefix: ".p",
+		Client:           http.DefaultClient,
+	}
+}
+
+// Report translates reports into Librato's tagged wire format and posts them.
+func (r *LibratoTagged) Report(reports []Report) error {
+	requests, err := r.taggedRequests(reports)
+	if err != nil {
+		return err
+	}
+	return doRequests(r.Client, requests)
+}
+
+func (r *LibratoTagged) taggedRequests(reports []Report) ([]*http.Request, error) {
+	measurements := make([]measurement, 0, len(reports))
+	for _, rep := range reports {
+		measurements = append(measurements, taggedMeasurements(rep, r.PercentilePrefix)...)
+	}
+	if len(measurements) == 0 {
+		return nil, nil
+	}
+
+	dest, user := resolveDestination(r.URL, taggedPath)
+	batchSize := batchSizeOrDefault(r.BatchSize)
+
+	nextEnd := func(e int) int {
+		e += batchSize
+		if l := len(measurements); e > l {
+			return l
+		}
+		return e
+	}
+
+	requests := make([]*http.Request, 0, len(measurements)/batchSize+1)
+	for batch, e := 0, nextEnd(0); batch < len(measurements); batch, e = e, nextEnd(e) {
+		body := struct {
+			Measurements []measurement `json:"measurements"`
+		}{
+			Measurements: measurements[batch:e],
+		}
+
+		req, err := newJSONRequest(dest, user, body)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, nil
+}
+
+// doRequests executes requests in order against client, returning the first
+// error or non-2xx status encountered.
+func doRequests(client *http.Client, requests []*http.Request) error {
+	for _, req := range requests {
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("librato: unexpected status %s", resp.Status)
+		}
+	}
+	return nil
+}
+
+func batchSizeOrDefault(n int) int {
+	if n > 0 {
+		return n
+	}
+	return defaultBatchSize
+}
+
+// resolveDestination resolves path against base, stripping any userinfo so
+// it isn't accidentally leaked in the returned *url.URL, and returns it
+// separately for basic auth.
+func resolveDestination(base *url.URL, path string) (*url.URL, *url.Userinfo) {
+	u := *base
+	user := u.User
+	u.User = nil
+	return u.ResolveReference(&url.URL{Path: path}), user
+}
+
+func newJSONRequest(dest *url.URL, user *url.Userinfo, body interface{}) (*http.Request, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, dest.String(), &buf)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		pw, _ := user.Password()
+		req.SetBasicAuth(user.Username(), pw)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// legacyGauges expands a Report into the legacy API's one-gauge-per-metric
+// form, fanning percentiles out into separate dotted gauge names.
+func legacyGauges(rep Report, percentilePrefix string) []gauge {
+	g := gauge{
+		Name: rep.Name, Period: rep.Period, Count: rep.Count,
+		Sum: rep.Sum, Min: rep.Min, Max: rep.Max, SumSq: rep.SumSq,
+		Attributes: rep.Attributes,
+	}
+	if len(rep.Percentiles) == 0 {
+		return []gauge{g}
+	}
+
+	out := make([]gauge, 0, 1+len(rep.Percentiles))
+	out = append(out, g)
+	for _, suffix := range []string{"50", "95", "99"} {
+		v, ok := rep.Percentiles[suffix]
+		if !ok {
+			continue
+		}
+		out = append(out, gauge{
+			Name: rep.Name + percentilePrefix + suffix, Period: rep.Period,
+			Count: 1, Sum: v, Min: v, Max: v, SumSq: v * v,
+			Attributes: rep.Attributes,
+		})
+	}
+	return out
+}
+
+// taggedMeasurements expands a Report into the tagged API's form, fanning
+// percentiles out into separate dotted measurement names, symmetric to
+// legacyGauges.
+func taggedMeasurements(rep Report, percentilePrefix string) []measurement {
+	t := rep.Timestamp.Unix()
+	m := measurement{
+		Name: rep.Name, Time: t, Period: rep.Period,
+		Attributes: rep.Attributes, Tags: rep.Tags,
+		Sum: rep.Sum, SumSq: rep.SumSq, Count: rep.Count,
+		Min: rep.Min, Max: rep.Max, Last: rep.Last, StdDev: rep.StdDev,
+	}
+	if len(rep.Percentiles) == 0 {
+		return []measurement{m}
+	}
+
+	out := make([]measurement, 0, 1+len(rep.Percentiles))
+	out = append(out, m)
+	for _, suffix := range []string{"50", "95", "99"} {
+		v, ok := rep.Percentiles[suffix]
+		if !ok {
+			continue
+		}
+		out = append(out, measurement{
+			Name: rep.Name + percentilePrefix + suffix, Time: t, Period: rep.Period,
+			Attributes: rep.Attributes, Tags: rep.Tags,
+			Count: 1, Sum: v, Min: v, Max: v, SumSq: v * v, Last: v,
+		})
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package librato
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLegacyGaugesFansOutPercentiles(t *testing.T) {
+	rep := Report{
+		Name: "request.latency", Period: 60, Count: 10, Sum: 100, Min: 1, Max: 50, SumSq: 2000,
+		Percentiles: map[string]float64{"50": 8, "95": 40, "99": 49},
+	}
+
+	gauges := legacyGauges(rep, ".p")
+	if len(gauges) != 4 {
+		t.Fatalf("len(gauges) = %d, want 4", len(gauges))
+	}
+	if gauges[0].Name != "request.latency" {
+		t.Errorf("gauges[0].Name = %q, want %q", gauges[0].Name, "request.latency")
+	}
+
+	want := map[string]float64{
+		"request.latency.p50": 8,
+		"request.latency.p95": 40,
+		"request.latency.p99": 49,
+	}
+	for _, g := range gauges[1:] {
+		v, ok := want[g.Name]
+		if !ok {
+			t.Errorf("unexpected gauge name %q", g.Name)
+			continue
+		}
+		if g.Sum != v {
+			t.Errorf("gauge %q Sum = %v, want %v", g.Name, g.Sum, v)
+		}
+	}
+}
+
+func TestLegacyGaugesNoPercentiles(t *testing.T) {
+	rep := Report{Name: "connections", Period: 60, Count: 1, Sum: 5, Min: 5, Max: 5}
+
+	gauges := legacyGauges(rep, ".p")
+	if len(gauges) != 1 {
+		t.Fatalf("len(gauges) = %d, want 1", len(gauges))
+	}
+	if gauges[0].Name != "connections" {
+		t.Errorf("gauges[0].Name = %q, want %q", gauges[0].Name, "connections")
+	}
+}
+
+func TestTaggedMeasurementsFansOutPercentiles(t *testing.T) {
+	rep := Report{
+		Name: "request.latency", Period: 60, Count: 10, Sum: 100, Min: 1, Max: 50, SumSq: 2000,
+		Tags: map[string]string{"method": "GET"}, Timestamp: time.Unix(1000, 0),
+		Percentiles: map[string]float64{"50": 8, "95": 40, "99": 49},
+	}
+
+	measurements := taggedMeasurements(rep, ".p")
+	if len(measurements) != 4 {
+		t.Fatalf("len(measurements) = %d, want 4", len(measurements))
+	}
+	if measurements[0].Name != "request.latency" {
+		t.Errorf("measurements[0].Name = %q, want %q", measurements[0].Name, "request.latency")
+	}
+	for _, m := range measurements {
+		if m.Tags["method"] != "GET" {
+			t.Errorf("measurement %q Tags[method] = %q, want GET", m.Name, m.Tags["method"])
+		}
+	}
+
+	want := map[string]float64{
+		"request.latency.p50": 8,
+		"request.latency.p95": 40,
+		"request.latency.p99": 49,
+	}
+	for _, m := range measurements[1:] {
+		v, ok := want[m.Name]
+		if !ok {
+			t.Errorf("unexpected measurement name %q", m.Name)
+			continue
+		}
+		if m.Sum != v {
+			t.Errorf("measurement %q Sum = %v, want %v", m.Name, m.Sum, v)
+		}
+	}
+}
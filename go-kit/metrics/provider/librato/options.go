@@ -0,0 +1,62 @@
+package librato
+
+// Option configures the Librato display attributes attached to a single
+// metric's gauge/measurement at batch time, e.g.
+//
+//	p.NewGauge("request.latency", librato.WithDisplayUnits("ms"), librato.WithSummarizeFunction("average"))
+type Option func(map[string]interface{})
+
+// WithDisplayUnits sets the gauge's display_units_short attribute.
+func WithDisplayUnits(units string) Option {
+	return func(attrs map[string]interface{}) { attrs["display_units_short"] = units }
+}
+
+// WithDisplayUnitsLong sets the gauge's display_units_long attribute.
+func WithDisplayUnitsLong(units string) Option {
+	return func(attrs map[string]interface{}) { attrs["display_units_long"] = units }
+}
+
+// WithSummarizeFunction sets the gauge's summarize_function attribute, e.g.
+// "average" or "sum".
+func WithSummarizeFunction(fn string) Option {
+	return func(attrs map[string]interface{}) { attrs["summarize_function"] = fn }
+}
+
+// WithAggregate sets the gauge's aggregate attribute, controlling whether
+// Librato combines values reported within the same measurement period.
+func WithAggregate(aggregate bool) Option {
+	return func(attrs map[string]interface{}) { attrs["aggregate"] = aggregate }
+}
+
+// WithColor sets the gauge's color attribute, e.g. "#ff0000".
+func WithColor(color string) Option {
+	return func(attrs map[string]interface{}) { attrs["color"] = color }
+}
+
+// WithDisplayMin sets the gauge's display_min attribute.
+func WithDisplayMin(min float64) Option {
+	return func(attrs map[string]interface{}) { attrs["display_min"] = min }
+}
+
+// WithDisplayMax sets the gauge's display_max attribute.
+func WithDisplayMax(max float64) Option {
+	return func(attrs map[string]interface{}) { attrs["display_max"] = max }
+}
+
+// applyOptions folds opts into a fresh attributes map, seeded with whatever
+// attrs the metric already carries (e.g. the display attributes Timer
+// derives from its unit). Returns nil if the result would be empty, so it
+// can be assigned directly to a gauge/measurement's omitempty Attributes.
+func applyOptions(base map[string]interface{}, opts []Option) map[string]interface{} {
+	if len(base) == 0 && len(opts) == 0 {
+		return nil
+	}
+	attrs := make(map[string]interface{}, len(base)+len(opts))
+	for k, v := range base {
+		attrs[k] = v
+	}
+	for _, opt := range opts {
+		opt(attrs)
+	}
+	return attrs
+}
@@ -0,0 +1,41 @@
+package librato
+
+import "testing"
+
+func TestApplyOptionsEmpty(t *testing.T) {
+	if got := applyOptions(nil, nil); got != nil {
+		t.Errorf("applyOptions(nil, nil) = %v, want nil", got)
+	}
+}
+
+func TestApplyOptionsMergesBaseAndOpts(t *testing.T) {
+	base := map[string]interface{}{"display_units_short": "ms"}
+	attrs := applyOptions(base, []Option{
+		WithSummarizeFunction("average"),
+		WithColor("#ff0000"),
+	})
+
+	if attrs["display_units_short"] != "ms" {
+		t.Errorf("display_units_short = %v, want ms", attrs["display_units_short"])
+	}
+	if attrs["summarize_function"] != "average" {
+		t.Errorf("summarize_function = %v, want average", attrs["summarize_function"])
+	}
+	if attrs["color"] != "#ff0000" {
+		t.Errorf("color = %v, want #ff0000", attrs["color"])
+	}
+
+	// base must not be mutated by applyOptions.
+	if _, ok := base["summarize_function"]; ok {
+		t.Errorf("applyOptions mutated its base map")
+	}
+}
+
+func TestApplyOptionsOverridesBase(t *testing.T) {
+	base := map[string]interface{}{"display_units_short": "ms"}
+	attrs := applyOptions(base, []Option{WithDisplayUnits("s")})
+
+	if attrs["display_units_short"] != "s" {
+		t.Errorf("display_units_short = %v, want s", attrs["display_units_short"])
+	}
+}
@@ -0,0 +1,145 @@
+package librato
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultBatchSize = 500
+
+// Provider produces gauges, counters and histograms and, on Flush, samples
+// them into a backend-neutral []Report and hands that batch to every
+// Reporter registered with AddReporter. New wires up a Librato reporter by
+// default; additional backends (a second Reporter implementation for
+// InfluxDB, Graphite, StatsD, ...) can be multiplexed in alongside it.
+type Provider struct {
+	mu sync.Mutex
+
+	interval time.Duration
+
+	gauges     map[string]*Gauge
+	counters   map[string]*Counter
+	histograms map[string]*Histogram
+
+	reporters []Reporter
+}
+
+// New returns a Provider that reports metrics to Librato at u, tagged with
+// source, using the v1/measurements (tagged) API if tagged is true, or the
+// legacy v1/metrics API otherwise.
+func New(u *url.URL, source string, interval time.Duration, tagged bool) *Provider {
+	p := &Provider{
+		interval:   interval,
+		gauges:     map[string]*Gauge{},
+		counters:   map[string]*Counter{},
+		histograms: map[string]*Histogram{},
+	}
+	if tagged {
+		p.AddReporter(NewLibratoTagged(u, source))
+	} else {
+		p.AddReporter(NewLibratoLegacy(u, source))
+	}
+	return p
+}
+
+// NewGauge returns a Librato gauge with the given name. Options control the
+// display attributes (aggregate, color, summarize_function, ...) Librato
+// attaches to the gauge's reported value.
+func (p *Provider) NewGauge(name string, opts ...Option) *Gauge {
+	g := newGauge(p, name, applyOptions(nil, opts))
+	p.registerGauge(g)
+	return g
+}
+
+// NewCounter returns a Librato counter with the given name. Each batch
+// interval it reports the delta accumulated since the previous interval,
+// then resets. Options control the gauge's Librato display attributes.
+func (p *Provider) NewCounter(name string, opts ...Option) *Counter {
+	c := newCounter(p, name, applyOptions(nil, opts))
+	p.registerCounter(c)
+	return c
+}
+
+// NewHistogram returns a Librato histogram with the given name. Each batch
+// interval it reports an aggregate value plus p50/p95/p99. Options control
+// the Librato display attributes shared by all four.
+func (p *Provider) NewHistogram(name string, opts ...Option) *Histogram {
+	h := newHistogram(p, name)
+	h.attrs = applyOptions(h.attrs, opts)
+	p.registerHistogram(h)
+	return h
+}
+
+// registerGauge and its siblings below key a metric by its fully-qualified,
+// label-folded name (see metricName) rather than its base name, so that
+// every distinct label combination produced via With is tracked and sampled
+// independently instead of only the base, label-less metric.
+
+func (p *Provider) registerGauge(g *Gauge) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.gauges[g.metricName()] = g
+}
+
+func (p *Provider) registerCounter(c *Counter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.counters[c.metricName()] = c
+}
+
+func (p *Provider) registerHistogram(h *Histogram) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.histograms[h.metricName()] = h
+}
+
+// sample reads every gauge, counter and histogram into a backend-neutral
+// []Report timestamped for this batch interval. Counters and histograms are
+// reset as they are read; gauges keep their value.
+func (p *Provider) sample() []Report {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	period := int(p.interval.Seconds())
+	now := time.Now()
+
+	reports := make([]Report, 0, len(p.gauges)+len(p.counters)+len(p.histograms))
+	for _, g := range p.gauges {
+		v := g.snapshot()
+		reports = append(reports, Report{
+			Name: g.metricName(), Tags: labelValuesToTags(g.lvs...), Attributes: g.attrs,
+			Count: 1, Sum: v, Min: v, Max: v, Last: v,
+			Period: period, Timestamp: now,
+		})
+	}
+	for _, c := range p.counters {
+		d := c.delta()
+		reports = append(reports, Report{
+			Name: c.metricName(), Tags: labelValuesToTags(c.lvs...), Attributes: c.attrs,
+			Count: 1, Sum: d, Min: d, Max: d, Last: d,
+			Period: period, Timestamp: now,
+		})
+	}
+	for _, h := range p.histograms {
+		if r, ok := h.report(period, now); ok {
+			reports = append(reports, r)
+		}
+	}
+	return reports
+}
+
+// metricName folds label values into a dotted Librato metric name, e.g.
+// NewHistogram("request.latency").With("method", "GET") reports as
+// "request.latency.GET".
+func metricName(name string, labelValues []string) string {
+	if len(labelValues) == 0 {
+		return name
+	}
+	parts := make([]string, 0, len(labelValues)/2)
+	for i := 1; i < len(labelValues); i += 2 {
+		parts = append(parts, labelValues[i])
+	}
+	return name + "." + strings.Join(parts, ".")
+}
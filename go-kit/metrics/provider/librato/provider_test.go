@@ -0,0 +1,95 @@
+package librato
+
+import "testing"
+
+// fakeReporter records the reports it's handed so tests can assert on them.
+type fakeReporter struct {
+	reports []Report
+}
+
+func (f *fakeReporter) Report(reports []Report) error {
+	f.reports = append(f.reports, reports...)
+	return nil
+}
+
+func (f *fakeReporter) byName(name string) (Report, bool) {
+	for _, r := range f.reports {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Report{}, false
+}
+
+func newTestProvider() (*Provider, *fakeReporter) {
+	p := &Provider{
+		gauges:     map[string]*Gauge{},
+		counters:   map[string]*Counter{},
+		histograms: map[string]*Histogram{},
+	}
+	f := &fakeReporter{}
+	p.AddReporter(f)
+	return p, f
+}
+
+func TestFlushReportsLabeledGauge(t *testing.T) {
+	p, f := newTestProvider()
+	g := p.NewGauge("request.count")
+	g.With("method", "GET").Set(42)
+
+	if err := p.Flush(); err != nil {
+		t.Fatalf("Flush() = %v, want nil", err)
+	}
+
+	r, ok := f.byName("request.count.GET")
+	if !ok {
+		t.Fatalf("labeled gauge %q was not reported", "request.count.GET")
+	}
+	if r.Sum != 42 {
+		t.Errorf("labeled gauge Sum = %v, want 42", r.Sum)
+	}
+}
+
+func TestFlushReportsLabeledCounter(t *testing.T) {
+	p, f := newTestProvider()
+	c := p.NewCounter("request.total")
+	labeled := c.With("method", "POST")
+	labeled.Add(3)
+	labeled.Add(4)
+
+	if err := p.Flush(); err != nil {
+		t.Fatalf("Flush() = %v, want nil", err)
+	}
+
+	r, ok := f.byName("request.total.POST")
+	if !ok {
+		t.Fatalf("labeled counter %q was not reported", "request.total.POST")
+	}
+	if r.Sum != 7 {
+		t.Errorf("labeled counter delta = %v, want 7", r.Sum)
+	}
+}
+
+func TestFlushReportsLabeledHistogramPercentiles(t *testing.T) {
+	p, f := newTestProvider()
+	h := p.NewHistogram("request.latency")
+	labeled := h.With("method", "GET")
+	for i := 1; i <= 10; i++ {
+		labeled.Observe(float64(i))
+	}
+
+	if err := p.Flush(); err != nil {
+		t.Fatalf("Flush() = %v, want nil", err)
+	}
+
+	r, ok := f.byName("request.latency.GET")
+	if !ok {
+		t.Fatalf("labeled histogram %q was not reported", "request.latency.GET")
+	}
+	if r.Count != 10 {
+		t.Errorf("labeled histogram Count = %d, want 10", r.Count)
+	}
+	if len(r.Percentiles) == 0 {
+		t.Errorf("labeled histogram reported no percentiles")
+	}
+}
@@ -0,0 +1,64 @@
+package librato
+
+import "time"
+
+// Report is a single metric's value for one sample interval, in a form
+// that's neutral to any particular backend's wire format.
+type Report struct {
+	Name       string
+	Tags       map[string]string
+	Attributes map[string]interface{}
+
+	Count  int64
+	Sum    float64
+	Min    float64
+	Max    float64
+	SumSq  float64
+	Last   float64
+	StdDev float64
+
+	// Percentiles holds, for histograms and timers, the value at each
+	// percentile keyed by its suffix ("50", "95", "99"). Empty for gauges
+	// and counters.
+	Percentiles map[string]float64
+
+	Period    int
+	Timestamp time.Time
+}
+
+// Reporter consumes a batch of Reports and ships them to a metrics backend
+// in that backend's native wire format, on whatever schedule and transport
+// suits it.
+type Reporter interface {
+	Report(reports []Report) error
+}
+
+// AddReporter registers r to receive every future sample batch, in addition
+// to whatever reporters are already registered.
+func (p *Provider) AddReporter(r Reporter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reporters = append(p.reporters, r)
+}
+
+// Flush samples every gauge, counter and histogram into a backend-neutral
+// []Report and hands it to every registered Reporter, returning the first
+// error encountered.
+func (p *Provider) Flush() error {
+	reports := p.sample()
+	if len(reports) == 0 {
+		return nil
+	}
+
+	p.mu.Lock()
+	reporters := append([]Reporter{}, p.reporters...)
+	p.mu.Unlock()
+
+	var firstErr error
+	for _, r := range reporters {
+		if err := r.Report(reports); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
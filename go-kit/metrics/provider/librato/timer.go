@@ -0,0 +1,58 @@
+package librato
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// durationUnitSuffix pulls the non-digit unit suffix off the end of a
+// time.Duration's String() form, e.g. "1ms" -> "ms", "250µs" -> "µs".
+var durationUnitSuffix = regexp.MustCompile(`^[0-9]+(\D+)$`)
+
+// Timer is a Histogram specialized for recording time.Duration samples. It
+// always observes in nanoseconds, but remembers the caller's preferred
+// display unit as display attributes that the Librato reporters attach to
+// the reported value so Librato knows how to scale it.
+type Timer struct {
+	*Histogram
+	unit time.Duration
+}
+
+func newTimer(p *Provider, name string, unit time.Duration, lvs ...string) *Timer {
+	h := newHistogram(p, name, lvs...)
+	h.attrs = displayAttributes(unit)
+	return &Timer{Histogram: h, unit: unit}
+}
+
+// With returns a new Timer with the label values appended to this one's.
+func (t *Timer) With(labelValues ...string) *Timer {
+	return &Timer{Histogram: t.Histogram.With(labelValues...), unit: t.unit}
+}
+
+// Observe records d, in nanoseconds, in the timer's underlying histogram.
+func (t *Timer) Observe(d time.Duration) {
+	t.Histogram.Observe(float64(d))
+}
+
+// NewTimer returns a Librato timer with the given name, displayed in unit
+// (e.g. time.Millisecond) on the Librato side. Further display attributes
+// may be layered on with Option, same as NewGauge/NewHistogram.
+func (p *Provider) NewTimer(name string, unit time.Duration, opts ...Option) *Timer {
+	t := newTimer(p, name, unit)
+	t.attrs = applyOptions(t.attrs, opts)
+	p.registerHistogram(t.Histogram)
+	return t
+}
+
+// displayAttributes builds the Librato display attributes that make a
+// nanosecond-denominated gauge/measurement render as unit in the Librato UI.
+func displayAttributes(unit time.Duration) map[string]interface{} {
+	attrs := map[string]interface{}{
+		"display_transform": fmt.Sprintf("x/%d", int64(unit)),
+	}
+	if m := durationUnitSuffix.FindStringSubmatch(unit.String()); m != nil {
+		attrs["display_units_short"] = m[1]
+	}
+	return attrs
+}
@@ -0,0 +1,48 @@
+package librato
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationUnitSuffix(t *testing.T) {
+	cases := []struct {
+		unit time.Duration
+		want string
+	}{
+		{time.Millisecond, "ms"},
+		{time.Microsecond, "µs"},
+		{time.Second, "s"},
+	}
+	for _, c := range cases {
+		m := durationUnitSuffix.FindStringSubmatch(c.unit.String())
+		if m == nil {
+			t.Errorf("durationUnitSuffix.FindStringSubmatch(%q) = nil, want a match", c.unit.String())
+			continue
+		}
+		if m[1] != c.want {
+			t.Errorf("durationUnitSuffix.FindStringSubmatch(%q) = %q, want %q", c.unit.String(), m[1], c.want)
+		}
+	}
+}
+
+func TestDisplayAttributes(t *testing.T) {
+	attrs := displayAttributes(time.Millisecond)
+
+	if got := attrs["display_transform"]; got != "x/1000000" {
+		t.Errorf("display_transform = %v, want x/1000000", got)
+	}
+	if got := attrs["display_units_short"]; got != "ms" {
+		t.Errorf("display_units_short = %v, want ms", got)
+	}
+}
+
+func TestTimerObserveRecordsNanoseconds(t *testing.T) {
+	timer := newTimer(nil, "test.timer", time.Millisecond)
+	timer.Observe(5 * time.Millisecond)
+
+	s := timer.Snapshot()
+	if s.Sum() != float64(5*time.Millisecond) {
+		t.Errorf("Sum() = %v, want %v", s.Sum(), float64(5*time.Millisecond))
+	}
+}
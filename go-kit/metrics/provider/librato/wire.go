@@ -0,0 +1,76 @@
+package librato
+
+import "math"
+
+// gauge is Librato's legacy (v1/metrics) wire format, used by LibratoLegacy.
+type gauge struct {
+	Name   string  `json:"name"`
+	Period int     `json:"period"`
+	Count  int64   `json:"count"`
+	Sum    float64 `json:"sum"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	SumSq  float64 `json:"sum_squares"`
+
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// measurement is Librato's tagged (v1/measurements) wire format, used by
+// LibratoTagged.
+type measurement struct {
+	Name   string `json:"name"`
+	Time   int64  `json:"time"`
+	Period int    `json:"period"`
+
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Tags       map[string]string      `json:"tags"`
+
+	Sum    float64 `json:"sum"`
+	SumSq  float64 `json:"-"`
+	Count  int64   `json:"count"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Last   float64 `json:"last"`
+	StdDev float64 `json:"stddev"`
+}
+
+func labelValuesToTags(labelValues ...string) map[string]string {
+	res := make(map[string]string)
+	l := len(labelValues)
+	for i := 0; i < l; i += 2 {
+		res[labelValues[i]] = labelValues[i+1]
+	}
+	return res
+}
+
+// The square of the distance from the mean is necessary in calculating
+// standard deviation. It's expressed as:
+//
+//   Σ (x - μ)²
+//
+// When doing time series datasets, we typically only hold on to the sum,
+// sum of squares, and the number of discrete values we've observed.
+//
+// Luckily, the square of distance from the mean can be expressed using
+// these as well:
+//
+//   Σ (x - μ)² = Σ (x² - 2xμ + μ²) = Σ x² + - Σ 2xμ + Σ μ²
+//                                  = sum_squares + -2(sum/n)(sum) + (sum / n)²
+//                                  = sum_squares + -2(sum²/n) + n(sum / n)²
+//                                  = sum_squares + -2(sum²/n) + n(sum² / n²)
+//                                  = sum_squares + -2(sum²/n) + sum²/n
+//                                  = sum_squares - sum²/n
+//
+func squareOfDistanceFromMean(sum, sumSquares, n float64) float64 {
+	return sumSquares - math.Pow(sum, 2)/n
+}
+
+// Standard deviation can be expressed, simply as:
+//
+//   √ (Σ (x - μ)² / N)
+//
+// Since we only have sum, sumSquares, and n in a time series context, we'll
+// use a derived formula from those values.
+func stddev(sum, sumSquares float64, count int64) float64 {
+	return math.Sqrt(squareOfDistanceFromMean(sum, sumSquares, float64(count)) / float64(count))
+}
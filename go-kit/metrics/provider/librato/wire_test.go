@@ -0,0 +1,28 @@
+package librato
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStddev(t *testing.T) {
+	// Observations: 2, 4, 4, 4, 5, 5, 7, 9 -> population stddev is 2.
+	values := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	var sum, sumsq float64
+	for _, v := range values {
+		sum += v
+		sumsq += v * v
+	}
+
+	got := stddev(sum, sumsq, int64(len(values)))
+	want := 2.0
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("stddev() = %v, want %v", got, want)
+	}
+}
+
+func TestStddevSingleValue(t *testing.T) {
+	if got := stddev(5, 25, 1); got != 0 {
+		t.Errorf("stddev() of a single observation = %v, want 0", got)
+	}
+}